@@ -0,0 +1,65 @@
+package docker
+
+import (
+	"context"
+	"time"
+)
+
+// BatchWindow and BatchMaxSize bound how long (and how large) BatchEvents
+// lets a burst accumulate before flushing, trading a small amount of
+// latency for far fewer frames written to slow consumers of a merged
+// stream.
+const (
+	BatchWindow  = 25 * time.Millisecond
+	BatchMaxSize = 64
+)
+
+// BatchEvents coalesces a stream of LogEvents into slices of up to
+// BatchMaxSize events, flushing whichever comes first: the size cap or
+// BatchWindow elapsing since the batch's first event. The returned channel
+// is closed once in is closed or ctx is done.
+func BatchEvents(ctx context.Context, in <-chan *LogEvent) <-chan []*LogEvent {
+	out := make(chan []*LogEvent)
+
+	go func() {
+		defer close(out)
+
+		var batch []*LogEvent
+		var timerC <-chan time.Time
+
+		flush := func() {
+			if len(batch) == 0 {
+				return
+			}
+			select {
+			case out <- batch:
+			case <-ctx.Done():
+			}
+			batch = nil
+			timerC = nil
+		}
+
+		for {
+			select {
+			case event, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				if batch == nil {
+					timerC = time.After(BatchWindow)
+				}
+				batch = append(batch, event)
+				if len(batch) >= BatchMaxSize {
+					flush()
+				}
+			case <-timerC:
+				flush()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
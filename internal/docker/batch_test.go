@@ -0,0 +1,67 @@
+package docker
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBatchEventsFlushesOnSize(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan *LogEvent)
+	out := BatchEvents(ctx, in)
+
+	go func() {
+		for i := 0; i < BatchMaxSize; i++ {
+			in <- &LogEvent{Message: "line"}
+		}
+	}()
+
+	select {
+	case batch := <-out:
+		if len(batch) != BatchMaxSize {
+			t.Errorf("expected a full batch of %d, got %d", BatchMaxSize, len(batch))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a size-triggered batch")
+	}
+}
+
+func TestBatchEventsFlushesOnWindow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan *LogEvent)
+	out := BatchEvents(ctx, in)
+
+	in <- &LogEvent{Message: "only one"}
+
+	select {
+	case batch := <-out:
+		if len(batch) != 1 {
+			t.Errorf("expected a batch of 1, got %d", len(batch))
+		}
+	case <-time.After(BatchWindow + time.Second):
+		t.Fatal("timed out waiting for a window-triggered batch")
+	}
+}
+
+func TestBatchEventsClosesOutputWhenInputCloses(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan *LogEvent)
+	out := BatchEvents(ctx, in)
+	close(in)
+
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Fatal("expected output channel to close without a pending batch")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for output channel to close")
+	}
+}
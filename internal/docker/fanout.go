@@ -0,0 +1,177 @@
+package docker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DropPolicy controls what a LogFanout source does when its buffer is full.
+type DropPolicy int
+
+const (
+	// DropOldest discards the oldest buffered event to make room for the
+	// new one, favoring freshness over completeness.
+	DropOldest DropPolicy = iota
+	// BlockWithTimeout blocks the producer for up to the fanout's timeout,
+	// favoring completeness over freshness, then drops the event.
+	BlockWithTimeout
+)
+
+// logSource is one container's bounded ring buffer and the goroutine that
+// forwards it into the fanout's merged output. Buffering and drop policy
+// are applied per source, so a burst from one container can only evict
+// that container's own backlog, and BlockWithTimeout on one source never
+// blocks another source's Push.
+type logSource struct {
+	ctx context.Context
+	buf chan *LogEvent
+}
+
+// LogFanout aggregates LogEvents from many per-container sources into a
+// single merged output channel. Each source gets its own bounded buffer
+// (see logSource), so a slow consumer can't starve one container's logs in
+// favor of another's, and Close cancels any source contexts still
+// registered so replaced containers don't leak their goroutine.
+type LogFanout struct {
+	mu       sync.Mutex
+	out      chan *LogEvent
+	dropped  chan struct{}
+	policy   DropPolicy
+	timeout  time.Duration
+	capacity int
+	sources  map[string]*logSource
+	cancels  map[string]context.CancelFunc
+	closed   bool
+}
+
+// NewLogFanout creates a LogFanout whose per-source buffers hold up to
+// capacity events before applying policy. timeout is only consulted by
+// BlockWithTimeout.
+func NewLogFanout(capacity int, policy DropPolicy, timeout time.Duration) *LogFanout {
+	return &LogFanout{
+		out:      make(chan *LogEvent),
+		dropped:  make(chan struct{}, 1),
+		policy:   policy,
+		timeout:  timeout,
+		capacity: capacity,
+		sources:  make(map[string]*logSource),
+		cancels:  make(map[string]context.CancelFunc),
+	}
+}
+
+// Add registers a source under key (typically "<host>/<containerID>"),
+// giving it its own buffer and a forwarding goroutine, and derives a
+// cancellable context for it. Registering the same key again cancels the
+// previous context first, so a container that gets replaced doesn't leave
+// its old StreamLogs goroutine running.
+func (f *LogFanout) Add(ctx context.Context, key string) context.Context {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if cancel, ok := f.cancels[key]; ok {
+		cancel()
+	}
+
+	childCtx, cancel := context.WithCancel(ctx)
+	f.cancels[key] = cancel
+
+	source := &logSource{ctx: childCtx, buf: make(chan *LogEvent, f.capacity)}
+	f.sources[key] = source
+
+	go f.forward(childCtx, source)
+
+	return childCtx
+}
+
+func (f *LogFanout) forward(ctx context.Context, source *logSource) {
+	for {
+		select {
+		case event := <-source.buf:
+			select {
+			case f.out <- event:
+			case <-ctx.Done():
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Push delivers event to the source registered under key, applying that
+// source's DropPolicy independently of every other source's backlog. A
+// Push for a key that was never Add-ed (or whose context is already
+// cancelled) is a no-op.
+func (f *LogFanout) Push(key string, event *LogEvent) {
+	f.mu.Lock()
+	source, ok := f.sources[key]
+	f.mu.Unlock()
+	if !ok || source.ctx.Err() != nil {
+		return
+	}
+
+	switch f.policy {
+	case BlockWithTimeout:
+		// A fresh timer per call, rather than reusing/resetting one: once a
+		// timeout already fired, its channel is drained, so a later
+		// Stop()-returned-false no longer means "drain it first" and
+		// blocks forever trying to read a value nobody will send.
+		timer := time.NewTimer(f.timeout)
+		select {
+		case source.buf <- event:
+			timer.Stop()
+		case <-timer.C:
+			f.signalDropped()
+		}
+	default: // DropOldest
+		select {
+		case source.buf <- event:
+		default:
+			select {
+			case <-source.buf:
+				f.signalDropped()
+			default:
+			}
+			select {
+			case source.buf <- event:
+			default:
+				f.signalDropped()
+			}
+		}
+	}
+}
+
+func (f *LogFanout) signalDropped() {
+	select {
+	case f.dropped <- struct{}{}:
+	default:
+	}
+}
+
+// Events returns the channel events from every source are merged onto.
+func (f *LogFanout) Events() <-chan *LogEvent {
+	return f.out
+}
+
+// Dropped fires (coalesced, non-blocking) whenever some source's Push had
+// to discard an event under back pressure, so callers can surface a gap
+// indicator.
+func (f *LogFanout) Dropped() <-chan struct{} {
+	return f.dropped
+}
+
+// Close cancels every source context still registered, unwinding their
+// StreamLogs and forwarding goroutines instead of leaking them.
+func (f *LogFanout) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return
+	}
+	f.closed = true
+	for _, cancel := range f.cancels {
+		cancel()
+	}
+}
@@ -0,0 +1,111 @@
+package docker
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLogFanoutMergesMultipleSources(t *testing.T) {
+	fanout := NewLogFanout(4, DropOldest, time.Second)
+	ctx := context.Background()
+
+	fanout.Add(ctx, "a")
+	fanout.Add(ctx, "b")
+
+	fanout.Push("a", &LogEvent{Message: "from a"})
+	fanout.Push("b", &LogEvent{Message: "from b"})
+
+	seen := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		select {
+		case event := <-fanout.Events():
+			seen[event.Message] = true
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for merged event")
+		}
+	}
+
+	if !seen["from a"] || !seen["from b"] {
+		t.Errorf("expected events from both sources, got %v", seen)
+	}
+}
+
+func TestLogFanoutPushToUnknownKeyIsNoop(t *testing.T) {
+	fanout := NewLogFanout(4, DropOldest, time.Second)
+	fanout.Push("never-added", &LogEvent{Message: "ignored"})
+
+	select {
+	case event := <-fanout.Events():
+		t.Fatalf("expected no event, got %v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestLogFanoutDropOldestSignalsDropped(t *testing.T) {
+	fanout := NewLogFanout(1, DropOldest, time.Second)
+	ctx := context.Background()
+	fanout.Add(ctx, "a")
+
+	// Nobody drains Events(), so the forwarding goroutine can hold at most
+	// one event and the one-slot buffer one more; the third push is forced
+	// to evict an older event.
+	fanout.Push("a", &LogEvent{Message: "first"})
+	fanout.Push("a", &LogEvent{Message: "second"})
+	fanout.Push("a", &LogEvent{Message: "third"})
+
+	select {
+	case <-fanout.Dropped():
+	case <-time.After(time.Second):
+		t.Fatal("expected Dropped to fire after exceeding source capacity")
+	}
+}
+
+func TestLogFanoutBlockWithTimeoutDoesNotHangOnRepeatedDrops(t *testing.T) {
+	// Capacity 0 means the first Push hands its event straight to the
+	// forwarding goroutine, which then blocks forever trying to deliver it
+	// to Events() (nobody reads it here) -- so every Push after that has
+	// nowhere to go and must time out.
+	fanout := NewLogFanout(0, BlockWithTimeout, 20*time.Millisecond)
+	fanout.Add(context.Background(), "a")
+
+	fanout.Push("a", &LogEvent{Message: "first"})
+
+	done := make(chan struct{})
+	go func() {
+		// Before the fix, the timeout from this call left Push's reused
+		// timer already drained, so the next call's Stop()-returned-false
+		// path read from a channel nothing would ever write to again.
+		fanout.Push("a", &LogEvent{Message: "second"})
+		fanout.Push("a", &LogEvent{Message: "third"})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Push hung after repeated timeouts on the same source")
+	}
+
+	select {
+	case <-fanout.Dropped():
+	case <-time.After(time.Second):
+		t.Fatal("expected at least one Dropped signal from the timed-out pushes")
+	}
+}
+
+func TestLogFanoutCloseStopsForwarding(t *testing.T) {
+	fanout := NewLogFanout(4, DropOldest, time.Second)
+	fanout.Add(context.Background(), "a")
+	fanout.Close()
+
+	// Close should be idempotent.
+	fanout.Close()
+
+	fanout.Push("a", &LogEvent{Message: "after close"})
+	select {
+	case event := <-fanout.Events():
+		t.Fatalf("expected no event after Close, got %v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,84 @@
+package docker
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDockerTime parses the `since`/`until` query values accepted by the
+// Docker/Podman compat logs endpoint. It accepts RFC3339(Nano) timestamps as
+// well as Unix seconds, optionally with a fractional nanosecond component
+// (e.g. "1700000000.123456789"), mirroring how the Docker daemon parses the
+// same parameters. An empty value yields the zero time.
+func ParseDockerTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+
+	secs, nanos := value, "0"
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		secs, nanos = value[:i], value[i+1:]
+	}
+
+	s, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Pad/truncate the fractional part to nanosecond precision.
+	for len(nanos) < 9 {
+		nanos += "0"
+	}
+	n, err := strconv.ParseInt(nanos[:9], 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(s, n), nil
+}
+
+// ParseTail parses the `tail` query value accepted by the Docker compat logs
+// endpoint: "all" (or empty) means no limit, otherwise it is the number of
+// lines to keep counting back from the end of the log.
+func ParseTail(value string) (int, error) {
+	if value == "" || value == "all" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// TailLimiter caps a stream of log events to the last n entries, discarding
+// older entries as new ones arrive. A limit of 0 means unlimited.
+type TailLimiter struct {
+	limit  int
+	events []*LogEvent
+}
+
+// NewTailLimiter creates a TailLimiter that keeps at most limit entries.
+func NewTailLimiter(limit int) *TailLimiter {
+	return &TailLimiter{limit: limit}
+}
+
+// Push adds an event, evicting the oldest entry once the limit is exceeded.
+func (t *TailLimiter) Push(event *LogEvent) {
+	t.events = append(t.events, event)
+	if t.limit > 0 && len(t.events) > t.limit {
+		t.events = t.events[len(t.events)-t.limit:]
+	}
+}
+
+// Events returns the retained events in order.
+func (t *TailLimiter) Events() []*LogEvent {
+	return t.events
+}
+
+// PrefixTimestamp formats a log line with a leading RFC3339Nano timestamp,
+// matching the `timestamps=true` behavior of `docker logs`.
+func PrefixTimestamp(event *LogEvent) string {
+	return time.Unix(0, event.Timestamp).Format(time.RFC3339Nano) + " " + event.Message
+}
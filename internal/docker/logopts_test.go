@@ -0,0 +1,97 @@
+package docker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDockerTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "empty", value: "", want: time.Time{}},
+		{name: "rfc3339nano", value: "2023-11-14T22:13:20.123456789Z", want: time.Date(2023, 11, 14, 22, 13, 20, 123456789, time.UTC)},
+		{name: "unix seconds", value: "1700000000", want: time.Unix(1700000000, 0)},
+		{name: "unix seconds with fraction", value: "1700000000.5", want: time.Unix(1700000000, 500000000)},
+		{name: "invalid", value: "not-a-time", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDockerTime(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error for %q, got none", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseDockerTime(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTail(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{name: "empty means unlimited", value: "", want: 0},
+		{name: "all means unlimited", value: "all", want: 0},
+		{name: "numeric", value: "100", want: 100},
+		{name: "invalid", value: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTail(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error for %q, got none", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTail(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTailLimiter(t *testing.T) {
+	limiter := NewTailLimiter(2)
+	for i := 0; i < 5; i++ {
+		limiter.Push(&LogEvent{Message: string(rune('a' + i))})
+	}
+
+	events := limiter.Events()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 retained events, got %d", len(events))
+	}
+	if events[0].Message != "d" || events[1].Message != "e" {
+		t.Errorf("expected the last two pushed events, got %q and %q", events[0].Message, events[1].Message)
+	}
+}
+
+func TestTailLimiterUnlimited(t *testing.T) {
+	limiter := NewTailLimiter(0)
+	for i := 0; i < 5; i++ {
+		limiter.Push(&LogEvent{Message: string(rune('a' + i))})
+	}
+
+	if len(limiter.Events()) != 5 {
+		t.Errorf("expected all 5 events retained with limit 0, got %d", len(limiter.Events()))
+	}
+}
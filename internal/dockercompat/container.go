@@ -0,0 +1,101 @@
+// Package dockercompat translates dozzle's internal container and log
+// types into the shapes returned by the Docker Engine API, so third-party
+// tooling built against docker-py, go-dockerclient, or testcontainers can
+// talk to dozzle as if it were a (read-only, aggregating) Docker daemon.
+package dockercompat
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/amir20/dozzle/internal/docker"
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+)
+
+// ToContainerSummary translates a dozzle docker.Container into the shape
+// returned by Docker Engine's compat `GET /containers/json`.
+func ToContainerSummary(c *docker.Container) types.Container {
+	return types.Container{
+		ID:      c.ID,
+		Names:   []string{"/" + c.Name},
+		Image:   c.Image,
+		State:   c.State,
+		Status:  c.State,
+		Labels:  c.Labels,
+		Created: c.Created,
+	}
+}
+
+// ToContainerJSON translates a dozzle docker.Container into the shape
+// returned by Docker Engine's compat `GET /containers/{id}/json`.
+func ToContainerJSON(c *docker.Container) types.ContainerJSON {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:   c.ID,
+			Name: "/" + c.Name,
+			State: &types.ContainerState{
+				Status:    c.State,
+				Running:   c.State == "running",
+				StartedAt: c.StartedAt.Format(types.RFC3339NanoFixed),
+			},
+		},
+		Config: &containertypes.Config{
+			Image:  c.Image,
+			Labels: c.Labels,
+			Tty:    c.Tty,
+		},
+	}
+}
+
+// FilterArgs is the decoded form of the `filters` query parameter Docker's
+// compat API accepts: a JSON object mapping a filter key (status, name,
+// label, ...) to the list of values a container may match any one of.
+type FilterArgs map[string][]string
+
+// ParseFilterArgs decodes the `filters` query parameter. An empty value
+// yields an empty FilterArgs that matches everything.
+func ParseFilterArgs(raw string) (FilterArgs, error) {
+	if raw == "" {
+		return FilterArgs{}, nil
+	}
+
+	var args FilterArgs
+	if err := json.Unmarshal([]byte(raw), &args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// Matches reports whether c satisfies every key present in the filter args;
+// within a key, any one of its values is sufficient (Docker's filter DSL
+// ANDs across keys and ORs within a key).
+func (args FilterArgs) Matches(c *docker.Container) bool {
+	for key, values := range args {
+		if !matchesAny(c, key, values) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAny(c *docker.Container, key string, values []string) bool {
+	for _, value := range values {
+		switch key {
+		case "status":
+			if c.State == value {
+				return true
+			}
+		case "name":
+			if c.Name == value {
+				return true
+			}
+		case "label":
+			k, v, hasValue := strings.Cut(value, "=")
+			if existing, ok := c.Labels[k]; ok && (!hasValue || existing == v) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package dockercompat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/docker"
+)
+
+func TestToContainerSummary(t *testing.T) {
+	c := &docker.Container{
+		ID:      "abc123",
+		Name:    "web",
+		Image:   "nginx:latest",
+		State:   "running",
+		Labels:  map[string]string{"env": "prod"},
+		Created: 1700000000,
+	}
+
+	summary := ToContainerSummary(c)
+	if summary.ID != c.ID {
+		t.Errorf("ID = %q, want %q", summary.ID, c.ID)
+	}
+	if len(summary.Names) != 1 || summary.Names[0] != "/web" {
+		t.Errorf("Names = %v, want [/web]", summary.Names)
+	}
+	if summary.State != "running" || summary.Status != "running" {
+		t.Errorf("State/Status = %q/%q, want running/running", summary.State, summary.Status)
+	}
+	if summary.Labels["env"] != "prod" {
+		t.Errorf("Labels[env] = %q, want prod", summary.Labels["env"])
+	}
+}
+
+func TestToContainerJSON(t *testing.T) {
+	startedAt := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	c := &docker.Container{
+		ID:        "abc123",
+		Name:      "web",
+		Image:     "nginx:latest",
+		State:     "running",
+		StartedAt: startedAt,
+		Tty:       true,
+		Labels:    map[string]string{"env": "prod"},
+	}
+
+	j := ToContainerJSON(c)
+	if j.ID != c.ID {
+		t.Errorf("ID = %q, want %q", j.ID, c.ID)
+	}
+	if j.Name != "/web" {
+		t.Errorf("Name = %q, want /web", j.Name)
+	}
+	if !j.State.Running {
+		t.Error("expected State.Running to be true for a running container")
+	}
+	if j.Config.Image != c.Image {
+		t.Errorf("Config.Image = %q, want %q", j.Config.Image, c.Image)
+	}
+	if !j.Config.Tty {
+		t.Error("expected Config.Tty to carry through from the container")
+	}
+}
+
+func TestParseFilterArgs(t *testing.T) {
+	t.Run("empty value matches everything", func(t *testing.T) {
+		args, err := ParseFilterArgs("")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !args.Matches(&docker.Container{State: "exited"}) {
+			t.Error("expected an empty FilterArgs to match any container")
+		}
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		if _, err := ParseFilterArgs("not json"); err == nil {
+			t.Error("expected an error for invalid filter JSON")
+		}
+	})
+
+	t.Run("decodes a populated filter", func(t *testing.T) {
+		args, err := ParseFilterArgs(`{"status":["running","restarting"]}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !args.Matches(&docker.Container{State: "running"}) {
+			t.Error("expected status=running to match a running container")
+		}
+		if args.Matches(&docker.Container{State: "exited"}) {
+			t.Error("expected status filter to reject a non-matching state")
+		}
+	})
+}
+
+func TestFilterArgsMatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      FilterArgs
+		container *docker.Container
+		want      bool
+	}{
+		{
+			name:      "no filters matches everything",
+			args:      FilterArgs{},
+			container: &docker.Container{State: "running"},
+			want:      true,
+		},
+		{
+			name:      "status OR within a key",
+			args:      FilterArgs{"status": {"exited", "running"}},
+			container: &docker.Container{State: "running"},
+			want:      true,
+		},
+		{
+			name:      "status no match",
+			args:      FilterArgs{"status": {"exited"}},
+			container: &docker.Container{State: "running"},
+			want:      false,
+		},
+		{
+			name:      "name match",
+			args:      FilterArgs{"name": {"web"}},
+			container: &docker.Container{Name: "web"},
+			want:      true,
+		},
+		{
+			name:      "label present without value",
+			args:      FilterArgs{"label": {"env"}},
+			container: &docker.Container{Labels: map[string]string{"env": "prod"}},
+			want:      true,
+		},
+		{
+			name:      "label key=value match",
+			args:      FilterArgs{"label": {"env=prod"}},
+			container: &docker.Container{Labels: map[string]string{"env": "prod"}},
+			want:      true,
+		},
+		{
+			name:      "label key=value mismatch",
+			args:      FilterArgs{"label": {"env=staging"}},
+			container: &docker.Container{Labels: map[string]string{"env": "prod"}},
+			want:      false,
+		},
+		{
+			name:      "ANDs across keys",
+			args:      FilterArgs{"status": {"running"}, "name": {"worker"}},
+			container: &docker.Container{State: "running", Name: "web"},
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.args.Matches(tt.container); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
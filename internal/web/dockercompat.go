@@ -0,0 +1,113 @@
+// This file covers four of the five Docker Engine compat endpoints:
+// list/inspect/stats/events. The fifth, `GET /containers/{id}/logs`, is
+// logs.go's logsCompat, which already serves since/until/tail/timestamps/
+// follow, so there's no separate compatContainerLogs handler here.
+package web
+
+import (
+	"net/http"
+
+	"github.com/goccy/go-json"
+
+	"github.com/amir20/dozzle/internal/docker"
+	"github.com/amir20/dozzle/internal/dockercompat"
+	"github.com/go-chi/chi/v5"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// compatListContainers serves Docker Engine compat `GET /containers/json`,
+// listing containers on the host named in the URL and supporting Docker's
+// `filters` query DSL (status, name, label).
+func (h *handler) compatListContainers(w http.ResponseWriter, r *http.Request) {
+	filterArgs, err := dockercompat.ParseFilterArgs(r.URL.Query().Get("filters"))
+	if err != nil {
+		http.Error(w, "invalid filters: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	host := hostKey(r)
+	containers, errs := h.multiHostService.ListAllContainersFiltered(func(c *docker.Container) bool {
+		return c.Host == host && filterArgs.Matches(c)
+	})
+	if len(errs) > 0 {
+		log.Warnf("error while listing containers for docker compat api %v", errs)
+	}
+
+	summaries := make([]interface{}, 0, len(containers))
+	for _, c := range containers {
+		summaries = append(summaries, dockercompat.ToContainerSummary(&c))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(summaries); err != nil {
+		log.Errorf("error encoding docker compat container list %v", err.Error())
+	}
+}
+
+// compatInspectContainer serves Docker Engine compat
+// `GET /containers/{id}/json`.
+func (h *handler) compatInspectContainer(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	containerService, err := h.multiHostService.FindContainer(hostKey(r), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(dockercompat.ToContainerJSON(&containerService.Container)); err != nil {
+		log.Errorf("error encoding docker compat container inspect %v", err.Error())
+	}
+}
+
+// compatContainerStats serves Docker Engine compat `GET /containers/{id}/stats`.
+// The real endpoint streams periodic `docker.StatsJSON` snapshots, but
+// nothing in ContainerService exposes a per-container stats source the way
+// LogsBetweenDates/RawLogs/StreamLogs do for logs, and CPU/memory numbers
+// aren't something to invent. Returning an honest 501 here rather than
+// fabricated stats; the real fix is adding a ContainerService.Stats(ctx)
+// source and translating its samples into docker.StatsJSON here, the same
+// way ToContainerJSON translates docker.Container.
+func (h *handler) compatContainerStats(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, "container stats are not available through the docker compat api yet", http.StatusNotImplemented)
+}
+
+// compatEvents serves Docker Engine compat `GET /events`, a JSONL stream of
+// container-started events for the host named in the URL, filtered down to
+// `type=container` since that's the only event category dozzle tracks
+// today. Container-stopped events are not sent on this stream.
+func (h *handler) compatEvents(w http.ResponseWriter, r *http.Request) {
+	if t := r.URL.Query().Get("type"); t != "" && t != "container" {
+		http.Error(w, "only type=container is supported", http.StatusBadRequest)
+		return
+	}
+
+	f, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported!", http.StatusInternalServerError)
+		return
+	}
+
+	host := hostKey(r)
+	w.Header().Set("Content-Type", "application/x-ndjson")
+
+	newContainers := make(chan docker.Container)
+	h.multiHostService.SubscribeContainersStarted(r.Context(), newContainers, func(c *docker.Container) bool {
+		return c.Host == host
+	})
+
+	encoder := json.NewEncoder(w)
+	for {
+		select {
+		case container := <-newContainers:
+			event := docker.ContainerEvent{ActorID: container.ID, Name: "container-started", Host: container.Host}
+			if err := encoder.Encode(event); err != nil {
+				log.Errorf("error encoding docker compat event %v", err.Error())
+			}
+			f.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
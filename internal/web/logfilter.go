@@ -0,0 +1,87 @@
+package web
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/amir20/dozzle/internal/docker"
+)
+
+// logFilter holds the optional `filter`/`regex`/`invert` query parameters
+// shared by the log streaming and history endpoints. A zero-value logFilter
+// matches everything.
+type logFilter struct {
+	substr string
+	re     *regexp.Regexp
+	invert bool
+}
+
+func parseLogFilter(r *http.Request) (*logFilter, error) {
+	query := r.URL.Query()
+	return newLogFilter(query.Get("filter"), query.Get("regex"), query.Get("invert") == "true")
+}
+
+// newLogFilter builds a logFilter from its constituent parts, also used by
+// the WebSocket transport to apply filter changes sent mid-stream.
+func newLogFilter(substr, pattern string, invert bool) (*logFilter, error) {
+	f := &logFilter{substr: substr, invert: invert}
+
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		f.re = re
+	}
+
+	return f, nil
+}
+
+func (f *logFilter) empty() bool {
+	return f == nil || (f.substr == "" && f.re == nil)
+}
+
+// matches reports whether event should be emitted, along with any named
+// capture groups from the regex match (nil if regex wasn't used or has no
+// named groups).
+func (f *logFilter) matches(event *docker.LogEvent) (bool, map[string]string) {
+	if f.empty() {
+		return true, nil
+	}
+
+	matched := true
+	var captures map[string]string
+
+	if f.substr != "" {
+		matched = strings.Contains(event.Message, f.substr)
+	}
+
+	if matched && f.re != nil {
+		groups := f.re.FindStringSubmatch(event.Message)
+		matched = groups != nil
+		if matched {
+			for i, name := range f.re.SubexpNames() {
+				if i != 0 && name != "" {
+					if captures == nil {
+						captures = make(map[string]string)
+					}
+					captures[name] = groups[i]
+				}
+			}
+		}
+	}
+
+	if f.invert {
+		matched = !matched
+	}
+
+	return matched, captures
+}
+
+// logEventWithMatches wraps a LogEvent with the named capture groups found
+// by an active `regex` filter so the frontend can highlight matched fields.
+type logEventWithMatches struct {
+	*docker.LogEvent
+	Matches map[string]string `json:"matches,omitempty"`
+}
@@ -0,0 +1,75 @@
+package web
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/amir20/dozzle/internal/docker"
+)
+
+func TestLogFilterMatches(t *testing.T) {
+	tests := []struct {
+		name        string
+		substr      string
+		pattern     string
+		invert      bool
+		message     string
+		wantMatched bool
+		wantMatches map[string]string
+	}{
+		{name: "empty filter matches everything", message: "anything", wantMatched: true},
+		{name: "substring match", substr: "error", message: "an error occurred", wantMatched: true},
+		{name: "substring no match", substr: "error", message: "all good", wantMatched: false},
+		{name: "inverted substring match becomes no match", substr: "error", invert: true, message: "an error occurred", wantMatched: false},
+		{name: "inverted substring no match becomes match", substr: "error", invert: true, message: "all good", wantMatched: true},
+		{
+			name:        "named capture groups are returned",
+			pattern:     `level=(?P<level>\w+)`,
+			message:     "level=warn msg=disk low",
+			wantMatched: true,
+			wantMatches: map[string]string{"level": "warn"},
+		},
+		{name: "regex no match", pattern: `^fatal`, message: "info: all good", wantMatched: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newLogFilter(tt.substr, tt.pattern, tt.invert)
+			if err != nil {
+				t.Fatalf("newLogFilter returned an error: %v", err)
+			}
+
+			matched, matches := f.matches(&docker.LogEvent{Message: tt.message})
+			if matched != tt.wantMatched {
+				t.Errorf("matches = %v, want %v", matched, tt.wantMatched)
+			}
+			if !reflect.DeepEqual(matches, tt.wantMatches) {
+				t.Errorf("captures = %v, want %v", matches, tt.wantMatches)
+			}
+		})
+	}
+}
+
+func TestLogFilterEmpty(t *testing.T) {
+	f, err := newLogFilter("", "", false)
+	if err != nil {
+		t.Fatalf("newLogFilter returned an error: %v", err)
+	}
+	if !f.empty() {
+		t.Error("expected a filter with no substring or pattern to be empty")
+	}
+
+	f, err = newLogFilter("error", "", false)
+	if err != nil {
+		t.Fatalf("newLogFilter returned an error: %v", err)
+	}
+	if f.empty() {
+		t.Error("expected a filter with a substring to not be empty")
+	}
+}
+
+func TestNewLogFilterInvalidRegex(t *testing.T) {
+	if _, err := newLogFilter("", "(", false); err == nil {
+		t.Error("expected an error for an invalid regex pattern")
+	}
+}
@@ -24,6 +24,27 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// downloadLogFormat is the negotiated body format for downloadLogs, chosen
+// from the request's Accept header.
+type downloadLogFormat int
+
+const (
+	downloadFormatText downloadLogFormat = iota
+	downloadFormatNDJSON
+	downloadFormatMultiplexed
+)
+
+func negotiateDownloadFormat(accept string) (downloadLogFormat, string) {
+	switch {
+	case strings.Contains(accept, "application/x-ndjson"):
+		return downloadFormatNDJSON, "ndjson"
+	case strings.Contains(accept, "application/vnd.docker.multiplexed-stream"):
+		return downloadFormatMultiplexed, "bin"
+	default:
+		return downloadFormatText, "log"
+	}
+}
+
 func (h *handler) downloadLogs(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	containerService, err := h.multiHostService.FindContainer(hostKey(r), id)
@@ -35,12 +56,14 @@ func (h *handler) downloadLogs(w http.ResponseWriter, r *http.Request) {
 	now := time.Now()
 	nowFmt := now.Format("2006-01-02T15-04-05")
 
-	contentDisposition := fmt.Sprintf("attachment; filename=%s-%s.log", containerService.Container.Name, nowFmt)
+	format, ext := negotiateDownloadFormat(r.Header.Get("Accept"))
+	filename := fmt.Sprintf("%s-%s.%s", containerService.Container.Name, nowFmt, ext)
+	contentDisposition := fmt.Sprintf("attachment; filename=%s", filename)
 
-	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+	gzipped := strings.Contains(r.Header.Get("Accept-Encoding"), "gzip")
+	if gzipped {
 		w.Header().Set("Content-Disposition", contentDisposition)
 		w.Header().Set("Content-Encoding", "gzip")
-		w.Header().Set("Content-Type", "application/text")
 	} else {
 		w.Header().Set("Content-Disposition", contentDisposition+".gz")
 		w.Header().Set("Content-Type", "application/gzip")
@@ -59,21 +82,196 @@ func (h *handler) downloadLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The body is always gzipped, same as before this handler grew format
+	// negotiation; Accept-Encoding only decides whether Content-Encoding
+	// tells the client to decompress it transparently or whether the
+	// client has to gunzip the downloaded .gz file itself.
 	zw := gzip.NewWriter(w)
 	defer zw.Close()
-	zw.Name = fmt.Sprintf("%s-%s.log", containerService.Container.Name, nowFmt)
+	zw.Name = filename
 	zw.Comment = "Logs generated by Dozzle"
 	zw.ModTime = now
+	var out io.Writer = zw
+
+	if gzipped {
+		switch format {
+		case downloadFormatNDJSON:
+			w.Header().Set("Content-Type", "application/x-ndjson")
+		case downloadFormatMultiplexed:
+			w.Header().Set("Content-Type", "application/vnd.docker.multiplexed-stream")
+		default:
+			w.Header().Set("Content-Type", "application/text")
+		}
+	}
+
+	switch format {
+	case downloadFormatNDJSON:
+		events, err := containerService.LogsBetweenDates(r.Context(), time.Time{}, now, stdTypes)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		encoder := json.NewEncoder(out)
+		for event := range events {
+			if err := encoder.Encode(event); err != nil {
+				log.Errorf("json encoding error while downloading logs %v", err.Error())
+			}
+		}
+	case downloadFormatMultiplexed:
+		reader, err := containerService.RawLogs(r.Context(), time.Time{}, now, stdTypes)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// Non-TTY logs are already framed in Docker's stdcopy format; pass
+		// them through untouched so the stdout/stderr distinction survives.
+		io.Copy(out, reader)
+	default:
+		reader, err := containerService.RawLogs(r.Context(), time.Time{}, now, stdTypes)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if containerService.Container.Tty {
+			io.Copy(out, reader)
+		} else {
+			stdcopy.StdCopy(out, out, reader)
+		}
+	}
+}
+
+// logsCompat serves a Docker/Podman compat `containers/{id}/logs` style
+// endpoint: `since`/`until` accept RFC3339 or Unix seconds, `tail=all|N`
+// caps the number of returned lines, `timestamps=true` prefixes each line
+// with its emit time, and `regex`/`filter`/`invert` apply the same log
+// filter the streaming endpoints accept. `follow=true` writes a plain-text
+// stream of new lines directly to the response as they arrive (writing
+// `tail` lines first, if requested) rather than delegating to the SSE
+// transport, since Docker/Podman compat clients expect raw log bytes, not
+// SSE framing. It shares the LogsBetweenDates/StreamLogs plumbing used by
+// downloadLogs, fetchLogsBetweenDates, and streamLogsForContainers.
+func (h *handler) logsCompat(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	containerService, err := h.multiHostService.FindContainer(hostKey(r), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+
+	since, err := docker.ParseDockerTime(query.Get("since"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid since: %v", err), http.StatusBadRequest)
+		return
+	}
+	until, err := docker.ParseDockerTime(query.Get("until"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid until: %v", err), http.StatusBadRequest)
+		return
+	}
+	if until.IsZero() {
+		until = time.Now()
+	}
 
-	reader, err := containerService.RawLogs(r.Context(), time.Time{}, now, stdTypes)
+	tail, err := docker.ParseTail(query.Get("tail"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid tail: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	timestamps := query.Get("timestamps") == "true"
+	follow := query.Get("follow") == "true"
+
+	var stdTypes docker.StdType
+	if query.Has("stdout") {
+		stdTypes |= docker.STDOUT
+	}
+	if query.Has("stderr") {
+		stdTypes |= docker.STDERR
+	}
+	if stdTypes == 0 {
+		http.Error(w, "stdout or stderr is required", http.StatusBadRequest)
+		return
+	}
+
+	filter, err := parseLogFilter(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid regex: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if follow {
+		f, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "Streaming unsupported!", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/text")
+
+		writeLine := func(event *docker.LogEvent) {
+			if ok, _ := filter.matches(event); !ok {
+				return
+			}
+			if timestamps {
+				fmt.Fprintln(w, docker.PrefixTimestamp(event))
+			} else {
+				fmt.Fprintln(w, event.Message)
+			}
+		}
+
+		if tail > 0 {
+			events, err := containerService.LogsBetweenDates(r.Context(), since, until, stdTypes)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			limiter := docker.NewTailLimiter(tail)
+			for event := range events {
+				limiter.Push(event)
+			}
+			for _, event := range limiter.Events() {
+				writeLine(event)
+			}
+			f.Flush()
+		}
+
+		local := make(chan *docker.LogEvent)
+		go func() {
+			if err := containerService.StreamLogs(r.Context(), until, stdTypes, local); err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, io.EOF) {
+				log.Errorf("error while streaming compat logs %v", err.Error())
+			}
+			close(local)
+		}()
+
+		for event := range local {
+			writeLine(event)
+			f.Flush()
+		}
+		return
+	}
+
+	events, err := containerService.LogsBetweenDates(r.Context(), since, until, stdTypes)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	if containerService.Container.Tty {
-		io.Copy(zw, reader)
-	} else {
-		stdcopy.StdCopy(zw, zw, reader)
+
+	limiter := docker.NewTailLimiter(tail)
+	for event := range events {
+		if ok, _ := filter.matches(event); ok {
+			limiter.Push(event)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/text")
+	for _, event := range limiter.Events() {
+		if timestamps {
+			fmt.Fprintln(w, docker.PrefixTimestamp(event))
+		} else {
+			fmt.Fprintln(w, event.Message)
+		}
 	}
 }
 
@@ -103,6 +301,12 @@ func (h *handler) fetchLogsBetweenDates(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	filter, err := parseLogFilter(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid regex: %v", err), http.StatusBadRequest)
+		return
+	}
+
 	events, err := containerService.LogsBetweenDates(r.Context(), from, to, stdTypes)
 	if err != nil {
 		log.Errorf("error while streaming logs %v", err.Error())
@@ -111,12 +315,15 @@ func (h *handler) fetchLogsBetweenDates(w http.ResponseWriter, r *http.Request)
 	buffer := utils.NewRingBuffer[*docker.LogEvent](500)
 
 	for event := range events {
-		buffer.Push(event)
+		if ok, _ := filter.matches(event); ok {
+			buffer.Push(event)
+		}
 	}
 
 	encoder := json.NewEncoder(w)
 	for _, event := range buffer.Data() {
-		if err := encoder.Encode(event); err != nil {
+		_, matches := filter.matches(event)
+		if err := encoder.Encode(logEventWithMatches{event, matches}); err != nil {
 			log.Errorf("json encoding error while streaming %v", err.Error())
 		}
 	}
@@ -183,6 +390,12 @@ func streamLogsForContainers(w http.ResponseWriter, r *http.Request, multiHostCl
 		return
 	}
 
+	lf, err := parseLogFilter(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid regex: %v", err), http.StatusBadRequest)
+		return
+	}
+
 	f, ok := w.(http.Flusher)
 	if !ok {
 		http.Error(w, "Streaming unsupported!", http.StatusInternalServerError)
@@ -195,23 +408,70 @@ func streamLogsForContainers(w http.ResponseWriter, r *http.Request, multiHostCl
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("X-Accel-Buffering", "no")
 
-	logs := make(chan *docker.LogEvent)
-	events := make(chan *docker.ContainerEvent, 1)
+	fanout, events, newContainers, streamLogs := setupLogSources(r, multiHostClient, filter, stdTypes)
 
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
-	existingContainers, errs := multiHostClient.ListAllContainersFiltered(filter)
-	if len(errs) > 0 {
-		log.Warnf("error while listing containers %v", errs)
+	for _, container := range multiHostClient.listExistingOrWarn(filter) {
+		go streamLogs(container)
 	}
 
+	pumpLogs(r.Context(), &sseSink{w, f}, fanout, events, newContainers, lf, streamLogs)
+
+	if log.IsLevelEnabled(log.DebugLevel) {
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		log.WithFields(log.Fields{
+			"allocated":      humanize.Bytes(m.Alloc),
+			"totalAllocated": humanize.Bytes(m.TotalAlloc),
+			"system":         humanize.Bytes(m.Sys),
+			"routines":       runtime.NumGoroutine(),
+		}).Debug("runtime mem stats")
+	}
+}
+
+// fanoutCapacity and fanoutBlockTimeout bound the LogFanout sitting between
+// each container's StreamLogs goroutine and the client: enough headroom to
+// absorb a burst, but small enough that a stalled client can't grow memory
+// without bound.
+const (
+	fanoutCapacity     = 256
+	fanoutBlockTimeout = 2 * time.Second
+)
+
+func dropPolicyFromRequest(r *http.Request) docker.DropPolicy {
+	if r.URL.Query().Get("dropPolicy") == "block" {
+		return docker.BlockWithTimeout
+	}
+	return docker.DropOldest
+}
+
+// setupLogSources wires up the fan-out and per-container goroutine shared
+// by every log-streaming transport (SSE, WebSocket): a bounded LogFanout fed
+// by one StreamLogs goroutine per matching container, a buffered events
+// channel for container-started/stopped notifications, and a newContainers
+// channel that triggers new goroutines as matching containers start.
+func setupLogSources(r *http.Request, multiHostClient *MultiHostService, filter ContainerFilter, stdTypes docker.StdType) (*docker.LogFanout, chan *docker.ContainerEvent, chan docker.Container, func(docker.Container)) {
+	fanout := docker.NewLogFanout(fanoutCapacity, dropPolicyFromRequest(r), fanoutBlockTimeout)
+	events := make(chan *docker.ContainerEvent, 1)
+	newContainers := make(chan docker.Container)
+
 	streamLogs := func(container docker.Container) {
 		containerService, err := multiHostClient.FindContainer(container.Host, container.ID)
 		if err != nil {
 			log.Errorf("error while finding container %v", err.Error())
 			return
 		}
-		err = containerService.StreamLogs(r.Context(), container.StartedAt, stdTypes, logs)
+
+		key := container.Host + "/" + container.ID
+		ctx := fanout.Add(r.Context(), key)
+		local := make(chan *docker.LogEvent)
+		go func() {
+			for event := range local {
+				fanout.Push(key, event)
+			}
+		}()
+
+		err = containerService.StreamLogs(ctx, container.StartedAt, stdTypes, local)
+		close(local)
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				log.WithError(err).Debugf("stream closed for container %v", container.Name)
@@ -222,57 +482,15 @@ func streamLogsForContainers(w http.ResponseWriter, r *http.Request, multiHostCl
 		}
 	}
 
-	for _, container := range existingContainers {
-		go streamLogs(container)
-	}
-
-	newContainers := make(chan docker.Container)
 	multiHostClient.SubscribeContainersStarted(r.Context(), newContainers, filter)
 
-loop:
-	for {
-		select {
-		case event := <-logs:
-			if buf, err := json.Marshal(event); err != nil {
-				log.Errorf("json encoding error while streaming %v", err.Error())
-			} else {
-				fmt.Fprintf(w, "data: %s\n", buf)
-			}
-			if event.Timestamp > 0 {
-				fmt.Fprintf(w, "id: %d\n", event.Timestamp)
-			}
-			fmt.Fprintf(w, "\n")
-			f.Flush()
-		case <-ticker.C:
-			fmt.Fprintf(w, ":ping \n\n")
-			f.Flush()
-		case container := <-newContainers:
-			events <- &docker.ContainerEvent{ActorID: container.ID, Name: "container-started", Host: container.Host}
-			go streamLogs(container)
-
-		case event := <-events:
-			log.Debugf("received container event %v", event)
-			if buf, err := json.Marshal(event); err != nil {
-				log.Errorf("json encoding error while streaming %v", err.Error())
-			} else {
-				fmt.Fprintf(w, "event: container-event\ndata: %s\n\n", buf)
-				f.Flush()
-			}
-
-		case <-r.Context().Done():
-			log.Debugf("context cancelled")
-			break loop
-		}
-	}
+	return fanout, events, newContainers, streamLogs
+}
 
-	if log.IsLevelEnabled(log.DebugLevel) {
-		var m runtime.MemStats
-		runtime.ReadMemStats(&m)
-		log.WithFields(log.Fields{
-			"allocated":      humanize.Bytes(m.Alloc),
-			"totalAllocated": humanize.Bytes(m.TotalAlloc),
-			"system":         humanize.Bytes(m.Sys),
-			"routines":       runtime.NumGoroutine(),
-		}).Debug("runtime mem stats")
+func (m *MultiHostService) listExistingOrWarn(filter ContainerFilter) []docker.Container {
+	existingContainers, errs := m.ListAllContainersFiltered(filter)
+	if len(errs) > 0 {
+		log.Warnf("error while listing containers %v", errs)
 	}
+	return existingContainers
 }
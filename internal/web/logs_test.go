@@ -0,0 +1,30 @@
+package web
+
+import "testing"
+
+func TestNegotiateDownloadFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		accept     string
+		wantFormat downloadLogFormat
+		wantExt    string
+	}{
+		{name: "ndjson", accept: "application/x-ndjson", wantFormat: downloadFormatNDJSON, wantExt: "ndjson"},
+		{name: "multiplexed", accept: "application/vnd.docker.multiplexed-stream", wantFormat: downloadFormatMultiplexed, wantExt: "bin"},
+		{name: "unrecognized falls back to text", accept: "application/json", wantFormat: downloadFormatText, wantExt: "log"},
+		{name: "empty falls back to text", accept: "", wantFormat: downloadFormatText, wantExt: "log"},
+		{name: "matches within a longer accept header", accept: "text/html, application/x-ndjson;q=0.9", wantFormat: downloadFormatNDJSON, wantExt: "ndjson"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, ext := negotiateDownloadFormat(tt.accept)
+			if format != tt.wantFormat {
+				t.Errorf("negotiateDownloadFormat(%q) format = %v, want %v", tt.accept, format, tt.wantFormat)
+			}
+			if ext != tt.wantExt {
+				t.Errorf("negotiateDownloadFormat(%q) ext = %q, want %q", tt.accept, ext, tt.wantExt)
+			}
+		})
+	}
+}
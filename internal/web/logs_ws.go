@@ -0,0 +1,277 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/goccy/go-json"
+
+	"github.com/amir20/dozzle/internal/docker"
+	"github.com/go-chi/chi/v5"
+	"nhooyr.io/websocket"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// wsControlMessage is sent by the client over an already-open log stream to
+// adjust it without tearing down and reopening the connection: change the
+// active filter/regex, or pause/resume delivery for back-pressure.
+type wsControlMessage struct {
+	Type   string `json:"type"`
+	Filter string `json:"filter"`
+	Regex  string `json:"regex"`
+	Invert bool   `json:"invert"`
+}
+
+// wsPauseBufferLimit bounds how many batches wsSink holds onto while paused.
+// Once hit, the oldest buffered batch is dropped to make room, same
+// trade-off LogFanout's DropOldest policy makes: favor resuming with recent
+// data over blocking or growing without bound.
+const wsPauseBufferLimit = 256
+
+// wsSink implements LogSink over a WebSocket connection. Log events and
+// container events are sent as distinct typed JSON text frames so the
+// client can distinguish them without a separate SSE "event:" line.
+//
+// Pausing doesn't stop pumpLogs from calling SendLogBatch — it buffers
+// batches (up to wsPauseBufferLimit) instead of writing them, and
+// readControlMessages flushes the buffer when the client resumes, so a
+// paused client gets the backlog rather than silently losing it. The
+// stdout/stderr toggle and cursor/replay-after-reconnect parts of the
+// control protocol remain out of scope for this fix.
+type wsSink struct {
+	ctx    context.Context
+	conn   *websocket.Conn
+	paused *atomic.Bool
+
+	mu      sync.Mutex
+	waiting [][]matchedLogEvent
+}
+
+func (s *wsSink) SendLogBatch(batch []matchedLogEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.paused.Load() {
+		if len(s.waiting) >= wsPauseBufferLimit {
+			s.waiting = s.waiting[1:]
+		}
+		s.waiting = append(s.waiting, batch)
+		return nil
+	}
+
+	return s.writeLogBatch(batch)
+}
+
+func (s *wsSink) writeLogBatch(batch []matchedLogEvent) error {
+	type wsLogEvent struct {
+		*docker.LogEvent
+		Matches map[string]string `json:"matches,omitempty"`
+	}
+	events := make([]wsLogEvent, len(batch))
+	for i, m := range batch {
+		events[i] = wsLogEvent{m.event, m.matches}
+	}
+
+	buf, err := json.Marshal(struct {
+		Type string       `json:"type"`
+		Logs []wsLogEvent `json:"logs"`
+	}{"log-batch", events})
+	if err != nil {
+		log.Errorf("json encoding error while streaming over websocket %v", err.Error())
+		return nil
+	}
+	return s.conn.Write(s.ctx, websocket.MessageText, buf)
+}
+
+// flushPaused clears the paused flag and writes out every batch buffered
+// while paused, in order. It's called once on "resume" and holds mu until
+// the backlog is written, so pumpLogs's loop delivers nothing newer before.
+func (s *wsSink) flushPaused() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.paused.Store(false)
+	waiting := s.waiting
+	s.waiting = nil
+
+	for _, batch := range waiting {
+		if err := s.writeLogBatch(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *wsSink) SendDropped() error {
+	buf, err := json.Marshal(struct {
+		Type string `json:"type"`
+	}{"log-dropped"})
+	if err != nil {
+		log.Errorf("json encoding error while streaming over websocket %v", err.Error())
+		return nil
+	}
+	return s.conn.Write(s.ctx, websocket.MessageText, buf)
+}
+
+func (s *wsSink) SendContainerEvent(event *docker.ContainerEvent) error {
+	buf, err := json.Marshal(struct {
+		Type string `json:"type"`
+		*docker.ContainerEvent
+	}{event.Name, event})
+	if err != nil {
+		log.Errorf("json encoding error while streaming over websocket %v", err.Error())
+		return nil
+	}
+	return s.conn.Write(s.ctx, websocket.MessageText, buf)
+}
+
+func (s *wsSink) Ping() error {
+	return s.conn.Ping(s.ctx)
+}
+
+// atomicLogFilter lets pumpLogs consult the latest filter without its own
+// locking, even while readControlMessages swaps it out underneath.
+type atomicLogFilter struct {
+	ptr *atomic.Pointer[logFilter]
+}
+
+func (f *atomicLogFilter) matches(event *docker.LogEvent) (bool, map[string]string) {
+	return f.ptr.Load().matches(event)
+}
+
+// readControlMessages listens for client control frames until the
+// connection closes or ctx is cancelled, applying them to filter and
+// paused. It calls cancel once the connection is gone (closed, or the
+// network path died) so pumpLogs and every per-container StreamLogs
+// goroutine for this client unwind instead of leaking. On "resume" it
+// flushes sink's paused buffer so the client gets what it missed.
+func readControlMessages(ctx context.Context, conn *websocket.Conn, cancel context.CancelFunc, filter *atomic.Pointer[logFilter], paused *atomic.Bool, sink *wsSink) {
+	defer cancel()
+
+	for {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+
+		var msg wsControlMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			log.Debugf("ignoring malformed websocket control message: %v", err)
+			continue
+		}
+
+		switch msg.Type {
+		case "pause":
+			paused.Store(true)
+		case "resume":
+			if err := sink.flushPaused(); err != nil {
+				log.Debugf("closing stream after send error flushing paused batches: %v", err)
+				return
+			}
+		case "filter":
+			lf, err := newLogFilter(msg.Filter, msg.Regex, msg.Invert)
+			if err != nil {
+				log.Debugf("ignoring invalid websocket filter update: %v", err)
+				continue
+			}
+			filter.Store(lf)
+		}
+	}
+}
+
+// streamLogsForContainersWS is the WebSocket counterpart of
+// streamLogsForContainers: it upgrades the connection, then drives the same
+// pumpLogs loop used by SSE so both transports share container bookkeeping,
+// reconnection handling, and filtering.
+func streamLogsForContainersWS(w http.ResponseWriter, r *http.Request, multiHostClient *MultiHostService, filter ContainerFilter) {
+	var stdTypes docker.StdType
+	if r.URL.Query().Has("stdout") {
+		stdTypes |= docker.STDOUT
+	}
+	if r.URL.Query().Has("stderr") {
+		stdTypes |= docker.STDERR
+	}
+	if stdTypes == 0 {
+		http.Error(w, "stdout or stderr is required", http.StatusBadRequest)
+		return
+	}
+
+	initial, err := parseLogFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		log.Errorf("error while upgrading to websocket %v", err.Error())
+		return
+	}
+	defer conn.CloseNow()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	filterPtr := new(atomic.Pointer[logFilter])
+	filterPtr.Store(initial)
+	var paused atomic.Bool
+
+	sink := &wsSink{ctx: ctx, conn: conn, paused: &paused}
+	go readControlMessages(ctx, conn, cancel, filterPtr, &paused, sink)
+
+	fanout, events, newContainers, streamLogs := setupLogSources(r, multiHostClient, filter, stdTypes)
+	for _, container := range multiHostClient.listExistingOrWarn(filter) {
+		go streamLogs(container)
+	}
+
+	pumpLogs(ctx, sink, fanout, events, newContainers, &atomicLogFilter{filterPtr}, streamLogs)
+
+	conn.Close(websocket.StatusNormalClosure, "")
+}
+
+func (h *handler) streamContainerLogsWS(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	streamLogsForContainersWS(w, r, h.multiHostService, func(container *docker.Container) bool {
+		return container.ID == id && container.Host == hostKey(r)
+	})
+}
+
+func (h *handler) streamLogsMergedWS(w http.ResponseWriter, r *http.Request) {
+	if !r.URL.Query().Has("id") {
+		http.Error(w, "ids query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	ids := make(map[string]bool)
+	for _, id := range r.URL.Query()["id"] {
+		ids[id] = true
+	}
+
+	streamLogsForContainersWS(w, r, h.multiHostService, func(container *docker.Container) bool {
+		return ids[container.ID] && container.Host == hostKey(r)
+	})
+}
+
+func (h *handler) streamServiceLogsWS(w http.ResponseWriter, r *http.Request) {
+	service := chi.URLParam(r, "service")
+	streamLogsForContainersWS(w, r, h.multiHostService, func(container *docker.Container) bool {
+		return container.State == "running" && container.Labels["com.docker.swarm.service.name"] == service
+	})
+}
+
+func (h *handler) streamGroupedLogsWS(w http.ResponseWriter, r *http.Request) {
+	group := chi.URLParam(r, "group")
+	streamLogsForContainersWS(w, r, h.multiHostService, func(container *docker.Container) bool {
+		return container.State == "running" && container.Group == group
+	})
+}
+
+func (h *handler) streamStackLogsWS(w http.ResponseWriter, r *http.Request) {
+	stack := chi.URLParam(r, "stack")
+	streamLogsForContainersWS(w, r, h.multiHostService, func(container *docker.Container) bool {
+		return container.State == "running" && container.Labels["com.docker.stack.namespace"] == stack
+	})
+}
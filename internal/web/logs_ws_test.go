@@ -0,0 +1,67 @@
+package web
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/amir20/dozzle/internal/docker"
+)
+
+func TestWsSinkBuffersBatchesWhilePaused(t *testing.T) {
+	var paused atomic.Bool
+	paused.Store(true)
+	sink := &wsSink{paused: &paused}
+
+	batch := []matchedLogEvent{{event: &docker.LogEvent{Message: "one"}}}
+	if err := sink.SendLogBatch(batch); err != nil {
+		t.Fatalf("unexpected error buffering a paused batch: %v", err)
+	}
+
+	if len(sink.waiting) != 1 {
+		t.Fatalf("expected 1 buffered batch, got %d", len(sink.waiting))
+	}
+}
+
+func TestWsSinkDropsOldestBufferedBatchPastLimit(t *testing.T) {
+	var paused atomic.Bool
+	paused.Store(true)
+	sink := &wsSink{paused: &paused}
+
+	for i := 0; i < wsPauseBufferLimit+5; i++ {
+		batch := []matchedLogEvent{{event: &docker.LogEvent{Message: "line"}}}
+		if err := sink.SendLogBatch(batch); err != nil {
+			t.Fatalf("unexpected error buffering a paused batch: %v", err)
+		}
+	}
+
+	if len(sink.waiting) != wsPauseBufferLimit {
+		t.Errorf("expected buffered batches capped at %d, got %d", wsPauseBufferLimit, len(sink.waiting))
+	}
+}
+
+func TestAtomicLogFilterReflectsSwappedFilter(t *testing.T) {
+	ptr := new(atomic.Pointer[logFilter])
+	f, err := newLogFilter("error", "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ptr.Store(f)
+
+	wrapped := &atomicLogFilter{ptr}
+
+	matched, _ := wrapped.matches(&docker.LogEvent{Message: "an error occurred"})
+	if !matched {
+		t.Error("expected atomicLogFilter to match via the stored filter")
+	}
+
+	replacement, err := newLogFilter("nope", "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ptr.Store(replacement)
+
+	matched, _ = wrapped.matches(&docker.LogEvent{Message: "an error occurred"})
+	if matched {
+		t.Error("expected atomicLogFilter to reflect a swapped-in filter")
+	}
+}
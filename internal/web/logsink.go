@@ -0,0 +1,156 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/amir20/dozzle/internal/docker"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// LogSink abstracts the transport used to deliver log and container events
+// to a client, so pumpLogs can drive either an SSE response or a WebSocket
+// connection with the same select loop.
+type LogSink interface {
+	SendLogBatch(batch []matchedLogEvent) error
+	SendContainerEvent(event *docker.ContainerEvent) error
+	SendDropped() error
+	Ping() error
+}
+
+// matchedLogEvent pairs a LogEvent with the named capture groups an active
+// regex filter found in it, if any.
+type matchedLogEvent struct {
+	event   *docker.LogEvent
+	matches map[string]string
+}
+
+// logMatcher is satisfied by *logFilter and lets pumpLogs consult a filter
+// that a WebSocket client may swap out mid-stream.
+type logMatcher interface {
+	matches(event *docker.LogEvent) (bool, map[string]string)
+}
+
+// pumpLogs is the transport-agnostic core of streamLogsForContainers: it
+// reads from fanout's bounded, batched output and fans log and container
+// events out to sink until the request context is cancelled or the sink
+// reports a write error (e.g. a closed WebSocket). Batching bursts behind
+// fanout means sink sees one write per docker.BatchWindow (or per
+// docker.BatchMaxSize events, whichever fills first) instead of one per log
+// line, and fanout's bounded buffer means a slow sink can't block the
+// per-container StreamLogs goroutines forever.
+func pumpLogs(
+	ctx context.Context,
+	sink LogSink,
+	fanout *docker.LogFanout,
+	events chan *docker.ContainerEvent,
+	newContainers chan docker.Container,
+	filter logMatcher,
+	streamLogs func(docker.Container),
+) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	batches := docker.BatchEvents(ctx, fanout.Events())
+
+loop:
+	for {
+		select {
+		case batch, ok := <-batches:
+			if !ok {
+				break loop
+			}
+			matched := make([]matchedLogEvent, 0, len(batch))
+			for _, event := range batch {
+				if ok, matches := filter.matches(event); ok {
+					matched = append(matched, matchedLogEvent{event, matches})
+				}
+			}
+			if len(matched) == 0 {
+				continue
+			}
+			if err := sink.SendLogBatch(matched); err != nil {
+				log.Debugf("closing stream after send error: %v", err)
+				break loop
+			}
+		case <-fanout.Dropped():
+			if err := sink.SendDropped(); err != nil {
+				log.Debugf("closing stream after send error: %v", err)
+				break loop
+			}
+		case <-ticker.C:
+			if err := sink.Ping(); err != nil {
+				log.Debugf("closing stream after ping error: %v", err)
+				break loop
+			}
+		case container := <-newContainers:
+			events <- &docker.ContainerEvent{ActorID: container.ID, Name: "container-started", Host: container.Host}
+			go streamLogs(container)
+		case event := <-events:
+			log.Debugf("received container event %v", event)
+			if err := sink.SendContainerEvent(event); err != nil {
+				log.Debugf("closing stream after send error: %v", err)
+				break loop
+			}
+		case <-ctx.Done():
+			log.Debugf("context cancelled")
+			break loop
+		}
+	}
+
+	fanout.Close()
+}
+
+// sseSink implements LogSink over a text/event-stream HTTP response.
+type sseSink struct {
+	w http.ResponseWriter
+	f http.Flusher
+}
+
+func (s *sseSink) SendLogBatch(batch []matchedLogEvent) error {
+	events := make([]logEventWithMatches, len(batch))
+	for i, m := range batch {
+		events[i] = logEventWithMatches{m.event, m.matches}
+	}
+
+	buf, err := json.Marshal(events)
+	if err != nil {
+		log.Errorf("json encoding error while streaming %v", err.Error())
+		return nil
+	}
+	fmt.Fprintf(s.w, "data: %s\n", buf)
+	if last := batch[len(batch)-1].event; last.Timestamp > 0 {
+		fmt.Fprintf(s.w, "id: %d\n", last.Timestamp)
+	}
+	fmt.Fprintf(s.w, "\n")
+	s.f.Flush()
+	return nil
+}
+
+func (s *sseSink) SendContainerEvent(event *docker.ContainerEvent) error {
+	buf, err := json.Marshal(event)
+	if err != nil {
+		log.Errorf("json encoding error while streaming %v", err.Error())
+		return nil
+	}
+	fmt.Fprintf(s.w, "event: container-event\ndata: %s\n\n", buf)
+	s.f.Flush()
+	return nil
+}
+
+func (s *sseSink) SendDropped() error {
+	fmt.Fprintf(s.w, "event: log-dropped\ndata: {}\n\n")
+	s.f.Flush()
+	return nil
+}
+
+func (s *sseSink) Ping() error {
+	fmt.Fprintf(s.w, ":ping \n\n")
+	s.f.Flush()
+	return nil
+}